@@ -0,0 +1,189 @@
+// Package admin implements a small JSON-RPC administration surface for the
+// tunnel node. It replaces the interactive stdin command loop with calls
+// that can be issued over a Unix socket or an HTTP listener, so the node can
+// run unattended (systemd, Docker without a TTY, remote operators).
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/ton-blockchain/adnl-tunnel/journal"
+	"github.com/ton-blockchain/adnl-tunnel/tunnel"
+	"github.com/xssnick/ton-payment-network/tonpayments"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/wallet"
+)
+
+// Deps are the node components the admin surface calls into. Payments,
+// Wallet and API are nil when payments are disabled; Journal is nil when
+// the journal is disabled.
+type Deps struct {
+	Gateway  *tunnel.Gateway
+	Payments *tonpayments.Service
+	Wallet   *wallet.Wallet
+	API      ton.APIClientWrapped
+	Journal  *journal.Journal
+	Log      zerolog.Logger
+}
+
+// Handler processes a single RPC method call.
+type Handler func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server is a minimal JSON-RPC-style dispatcher exposed over a Unix socket
+// and, optionally, HTTP. Requests and responses carry an id, a method and
+// params or a result; errors are plain strings, and there is no "jsonrpc"
+// version member as in JSON-RPC 2.0. Method names are namespaced as "Service.Method"
+// (e.g. "Tunnel.Stats", "Wallet.Transfer"), mirroring net/rpc conventions.
+type Server struct {
+	deps Deps
+	log  zerolog.Logger
+
+	token string // required for methods registered via Protected
+
+	mu       sync.RWMutex
+	handlers map[string]registeredHandler
+}
+
+type registeredHandler struct {
+	fn        Handler
+	protected bool
+}
+
+// NewServer creates an admin server with the built-in Tunnel/Payments/Wallet
+// methods registered. token, if non-empty, is required (as the "token"
+// request field) to invoke any method marked protected, which covers every
+// call that can move funds or mutate on-chain state.
+func NewServer(deps Deps, token string) *Server {
+	s := &Server{
+		deps:     deps,
+		log:      deps.Log.With().Str("component", "admin").Logger(),
+		token:    token,
+		handlers: map[string]registeredHandler{},
+	}
+	s.registerBuiltins()
+	return s
+}
+
+// Register adds or overrides a method handler.
+func (s *Server) Register(method string, protected bool, fn Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = registeredHandler{fn: fn, protected: protected}
+}
+
+// ListenUnix starts serving JSON-RPC requests on a Unix domain socket at
+// path. Each connection may carry a stream of JSON request objects, each
+// answered in turn until the client closes it. It removes a stale socket file
+// left behind by a previous run before binding.
+func (s *Server) ListenUnix(path string) error {
+	_ = os.Remove(path)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listen unix socket: %w", err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				s.log.Error().Err(err).Msg("admin socket accept failed")
+				return
+			}
+			go s.serveConn(conn)
+		}
+	}()
+
+	return nil
+}
+
+// ListenHTTP starts serving JSON-RPC requests as HTTP POST /rpc on addr.
+func (s *Server) ListenHTTP(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", s.serveHTTP)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen admin http: %w", err)
+	}
+
+	go func() {
+		if err := http.Serve(ln, mux); err != nil {
+			s.log.Error().Err(err).Msg("admin http server stopped")
+		}
+	}()
+
+	return nil
+}
+
+type request struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Token  string          `json:"token,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     uint64 `json:"id"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		enc.Encode(s.dispatch(context.Background(), req))
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp := s.dispatch(r.Context(), req)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) response {
+	s.mu.RLock()
+	h, ok := s.handlers[req.Method]
+	s.mu.RUnlock()
+
+	if !ok {
+		return response{ID: req.ID, Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+
+	// A protected method is only rejected when a token is actually
+	// configured and the caller didn't present it. With no -admin-token
+	// set, protected calls go through unauthenticated (see the startup
+	// warning in cmd/node) rather than being permanently locked out.
+	if h.protected && s.token != "" && req.Token != s.token {
+		return response{ID: req.ID, Error: "unauthorized"}
+	}
+
+	result, err := h.fn(ctx, req.Params)
+	if err != nil {
+		return response{ID: req.ID, Error: err.Error()}
+	}
+
+	return response{ID: req.ID, Result: result}
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func newTestServer(token string) *Server {
+	return &Server{
+		token:    token,
+		handlers: map[string]registeredHandler{},
+	}
+}
+
+func TestDispatchUnknownMethod(t *testing.T) {
+	s := newTestServer("")
+	resp := s.dispatch(context.Background(), request{ID: 1, Method: "Nope.Nope"})
+	if resp.Error == "" {
+		t.Fatalf("expected an error for an unknown method, got %+v", resp)
+	}
+}
+
+func TestDispatchProtectedWithoutToken(t *testing.T) {
+	s := newTestServer("")
+	s.Register("Wallet.Transfer", true, func(ctx context.Context, params json.RawMessage) (any, error) {
+		return "ok", nil
+	})
+
+	resp := s.dispatch(context.Background(), request{ID: 1, Method: "Wallet.Transfer"})
+	if resp.Error != "" {
+		t.Fatalf("protected call should go through when no token is configured, got error: %s", resp.Error)
+	}
+	if resp.Result != "ok" {
+		t.Fatalf("expected result %q, got %v", "ok", resp.Result)
+	}
+}
+
+func TestDispatchProtectedWithToken(t *testing.T) {
+	s := newTestServer("secret")
+	s.Register("Wallet.Transfer", true, func(ctx context.Context, params json.RawMessage) (any, error) {
+		return "ok", nil
+	})
+
+	if resp := s.dispatch(context.Background(), request{ID: 1, Method: "Wallet.Transfer"}); resp.Error != "unauthorized" {
+		t.Fatalf("expected unauthorized without a token, got %+v", resp)
+	}
+
+	if resp := s.dispatch(context.Background(), request{ID: 1, Method: "Wallet.Transfer", Token: "wrong"}); resp.Error != "unauthorized" {
+		t.Fatalf("expected unauthorized with a wrong token, got %+v", resp)
+	}
+
+	resp := s.dispatch(context.Background(), request{ID: 1, Method: "Wallet.Transfer", Token: "secret"})
+	if resp.Error != "" {
+		t.Fatalf("expected the call to succeed with the correct token, got error: %s", resp.Error)
+	}
+}
+
+func TestDispatchUnprotectedIgnoresToken(t *testing.T) {
+	s := newTestServer("secret")
+	s.Register("Tunnel.Stats", false, func(ctx context.Context, params json.RawMessage) (any, error) {
+		return "ok", nil
+	})
+
+	resp := s.dispatch(context.Background(), request{ID: 1, Method: "Tunnel.Stats"})
+	if resp.Error != "" {
+		t.Fatalf("unprotected methods shouldn't require a token, got error: %s", resp.Error)
+	}
+}
@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ton-blockchain/adnl-tunnel/journal"
+	"github.com/xssnick/ton-payment-network/tonpayments/db"
+	tonaddr "github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+)
+
+func (s *Server) registerBuiltins() {
+	s.Register("Tunnel.Stats", false, s.tunnelStats)
+	s.Register("Tunnel.Speed", false, s.tunnelSpeed)
+	s.Register("Tunnel.Bandwidth", false, s.tunnelBandwidth)
+	s.Register("Payments.Balance", false, s.paymentsBalance(false))
+	s.Register("Payments.Capacity", false, s.paymentsBalance(true))
+	s.Register("Payments.ListChannels", false, s.paymentsListChannels)
+	s.Register("Wallet.Balance", false, s.walletBalance)
+	s.Register("Wallet.Transfer", true, s.walletTransfer)
+	s.Register("Journal.Tail", false, s.journalTail)
+	s.Register("Journal.Since", false, s.journalSince)
+}
+
+func (s *Server) requireJournal() error {
+	if s.deps.Journal == nil {
+		return fmt.Errorf("journal is not enabled")
+	}
+	return nil
+}
+
+type journalTailParams struct {
+	Types []journal.EventType `json:"types"`
+	Limit int                 `json:"limit"`
+}
+
+func (s *Server) journalTail(ctx context.Context, params json.RawMessage) (any, error) {
+	if err := s.requireJournal(); err != nil {
+		return nil, err
+	}
+
+	var p journalTailParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+	}
+
+	return s.deps.Journal.Tail(journal.Filter{Types: p.Types, Limit: p.Limit}), nil
+}
+
+type journalSinceParams struct {
+	Seq uint64 `json:"seq"`
+}
+
+func (s *Server) journalSince(ctx context.Context, params json.RawMessage) (any, error) {
+	if err := s.requireJournal(); err != nil {
+		return nil, err
+	}
+
+	var p journalSinceParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	return s.deps.Journal.Since(p.Seq), nil
+}
+
+func (s *Server) requirePayments() error {
+	if s.deps.Payments == nil {
+		return fmt.Errorf("payments are not enabled")
+	}
+	return nil
+}
+
+func (s *Server) tunnelStats(ctx context.Context, params json.RawMessage) (any, error) {
+	return s.deps.Gateway.GetPacketsStats(), nil
+}
+
+type speedParams struct {
+	IntervalMs int `json:"interval_ms"`
+}
+
+// tunnelSpeed returns a single packets-per-second sample for every section,
+// computed over the requested interval (default 1s). Unlike the old "speed"
+// stdin toggle, each call is self-contained so it can be polled by a client.
+func (s *Server) tunnelSpeed(ctx context.Context, params json.RawMessage) (any, error) {
+	var p speedParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+	}
+	if p.IntervalMs <= 0 {
+		p.IntervalMs = 1000
+	}
+
+	before := s.deps.Gateway.GetPacketsStats()
+
+	timer := time.NewTimer(time.Duration(p.IntervalMs) * time.Millisecond)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	after := s.deps.Gateway.GetPacketsStats()
+
+	type sectionRate struct {
+		Routed   uint64 `json:"routed_per_s"`
+		Sent     uint64 `json:"sent_per_s"`
+		Received uint64 `json:"received_per_s"`
+	}
+
+	seconds := float64(p.IntervalMs) / 1000
+	out := map[string]sectionRate{}
+	for section, a := range after {
+		b := before[section]
+		if b == nil {
+			continue
+		}
+		out[section] = sectionRate{
+			Routed:   uint64(float64(a.Routed-b.Routed) / seconds),
+			Sent:     uint64(float64(a.Sent-b.Sent) / seconds),
+			Received: uint64(float64(a.Received-b.Received) / seconds),
+		}
+	}
+
+	return out, nil
+}
+
+// tunnelBandwidth reports byte counters and rolling rates, complementing
+// Tunnel.Stats' packet counters. Only per-section attribution is
+// populated today; see tunnel.BandwidthSampler's doc comment for why
+// per-peer and per-hop keys aren't produced yet.
+func (s *Server) tunnelBandwidth(ctx context.Context, params json.RawMessage) (any, error) {
+	return s.deps.Gateway.GetBandwidthStats(), nil
+}
+
+func (s *Server) paymentsBalance(capacity bool) Handler {
+	return func(ctx context.Context, params json.RawMessage) (any, error) {
+		if err := s.requirePayments(); err != nil {
+			return nil, err
+		}
+
+		list, err := s.deps.Payments.ListChannels(ctx, nil, db.ChannelStateActive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list channels: %w", err)
+		}
+
+		amount := big.NewInt(0)
+		for _, channel := range list {
+			v, _, err := channel.CalcBalance(capacity)
+			if err != nil {
+				continue
+			}
+			amount = amount.Add(amount, v)
+		}
+
+		return map[string]string{"ton": tlb.FromNanoTON(amount).String()}, nil
+	}
+}
+
+func (s *Server) paymentsListChannels(ctx context.Context, params json.RawMessage) (any, error) {
+	if err := s.requirePayments(); err != nil {
+		return nil, err
+	}
+
+	return s.deps.Payments.ListChannels(ctx, nil, db.ChannelStateAny)
+}
+
+func (s *Server) walletBalance(ctx context.Context, params json.RawMessage) (any, error) {
+	if s.deps.Wallet == nil {
+		return nil, fmt.Errorf("payments are not enabled")
+	}
+
+	blk, err := s.deps.API.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current masterchain info: %w", err)
+	}
+
+	balance, err := s.deps.Wallet.GetBalance(ctx, blk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	return map[string]string{"ton": balance.String()}, nil
+}
+
+type transferParams struct {
+	To      string `json:"to"`
+	Amount  string `json:"amount"`
+	Comment string `json:"comment"`
+}
+
+func (s *Server) walletTransfer(ctx context.Context, params json.RawMessage) (any, error) {
+	if s.deps.Wallet == nil {
+		return nil, fmt.Errorf("payments are not enabled")
+	}
+
+	var p transferParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	addr, err := tonaddr.ParseAddr(p.To)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect format of address: %w", err)
+	}
+
+	amt, err := tlb.FromTON(p.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect format of amount: %w", err)
+	}
+
+	s.log.Info().Str("to_address", addr.String()).Str("amount", amt.String()).Msg("transferring...")
+
+	tx, _, err := s.deps.Wallet.TransferWaitTransaction(ctx, addr, amt, p.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer: %w", err)
+	}
+
+	if s.deps.Journal != nil {
+		s.deps.Journal.Record(journal.EventWalletTransfer, map[string]string{
+			"to":     addr.String(),
+			"amount": amt.String(),
+			"hash":   base64.URLEncoding.EncodeToString(tx.Hash),
+		})
+	}
+
+	return map[string]string{"tx_hash": base64.URLEncoding.EncodeToString(tx.Hash)}, nil
+}
@@ -0,0 +1,189 @@
+// Command adnl-tunnel-ctl is a thin client for the tunnel node's admin RPC
+// surface (see package admin). It replaces typing commands into the node's
+// own stdin, which doesn't work when the node is run as a daemon.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var (
+	SocketPath = flag.String("socket", "/var/run/adnl-tunnel/admin.sock", "Path to the node's admin Unix socket")
+	AdminAddr  = flag.String("addr", "", "Admin HTTP address (host:port), used instead of -socket when set")
+	Token      = flag.String("token", "", "Admin auth token, required for destructive calls (e.g. wallet-transfer) when the node sets -admin-token")
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	method, params, err := buildCall(args[0], args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	result, err := call(method, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `adnl-tunnel-ctl - control a running adnl-tunnel node
+
+Usage:
+  adnl-tunnel-ctl [-socket path | -addr host:port] [-token TOKEN] <command> [args...]
+
+Commands:
+  stats                              show per-section packet counters
+  speed [interval_ms]                show per-section packets/sec
+  bandwidth                          show per-section byte counters and rates
+  balance                            show summarized channel balance
+  capacity                           show remaining channel capacity
+  channels                           list payment channels
+  wallet-ton-balance                 show on-chain wallet balance
+  wallet-ton-transfer <to> <amount> [comment]   transfer TON (requires -token if the node sets -admin-token)
+  journal-tail [limit]               show recent journal events
+  journal-since <seq>                show journal events after sequence number
+  reload                             re-read config.json and apply price/listener changes`)
+}
+
+func buildCall(cmd string, rest []string) (method string, params any, err error) {
+	switch cmd {
+	case "stats":
+		return "Tunnel.Stats", nil, nil
+	case "bandwidth":
+		return "Tunnel.Bandwidth", nil, nil
+	case "reload":
+		return "Tunnel.Reload", nil, nil
+	case "speed":
+		p := map[string]int{}
+		if len(rest) > 0 {
+			var ms int
+			if _, err := fmt.Sscanf(rest[0], "%d", &ms); err == nil {
+				p["interval_ms"] = ms
+			}
+		}
+		return "Tunnel.Speed", p, nil
+	case "balance":
+		return "Payments.Balance", nil, nil
+	case "capacity":
+		return "Payments.Capacity", nil, nil
+	case "channels":
+		return "Payments.ListChannels", nil, nil
+	case "journal-tail":
+		p := map[string]int{}
+		if len(rest) > 0 {
+			var limit int
+			if _, err := fmt.Sscanf(rest[0], "%d", &limit); err == nil {
+				p["limit"] = limit
+			}
+		}
+		return "Journal.Tail", p, nil
+	case "journal-since":
+		if len(rest) < 1 {
+			return "", nil, fmt.Errorf("usage: journal-since <seq>")
+		}
+		var seq uint64
+		if _, err := fmt.Sscanf(rest[0], "%d", &seq); err != nil {
+			return "", nil, fmt.Errorf("invalid seq %q", rest[0])
+		}
+		return "Journal.Since", map[string]uint64{"seq": seq}, nil
+	case "wallet-ton-balance":
+		return "Wallet.Balance", nil, nil
+	case "wallet-ton-transfer":
+		if len(rest) < 2 {
+			return "", nil, fmt.Errorf("usage: wallet-ton-transfer <to> <amount> [comment]")
+		}
+		return "Wallet.Transfer", map[string]string{
+			"to":      rest[0],
+			"amount":  rest[1],
+			"comment": strings.Join(rest[2:], " "),
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+type request struct {
+	ID     uint64 `json:"id"`
+	Method string `json:"method"`
+	Token  string `json:"token,omitempty"`
+	Params any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+func call(method string, params any) (json.RawMessage, error) {
+	req := request{ID: 1, Method: method, Token: *Token, Params: params}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if *AdminAddr != "" {
+		return callHTTP(*AdminAddr, body)
+	}
+	return callUnix(*SocketPath, body)
+}
+
+func callUnix(path string, body []byte) (json.RawMessage, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("dial admin socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write(body); err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	var resp response
+	if err = json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+	return resp.Result, nil
+}
+
+func callHTTP(addr string, body []byte) (json.RawMessage, error) {
+	url := "http://" + addr + "/rpc"
+	r, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("post admin request: %w", err)
+	}
+	defer r.Body.Close()
+
+	var resp response
+	if err = json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+	return resp.Result, nil
+}
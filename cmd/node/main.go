@@ -6,14 +6,19 @@ import (
 	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"github.com/mattn/go-isatty"
 	"github.com/natefinch/lumberjack"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/ton-blockchain/adnl-tunnel/admin"
 	"github.com/ton-blockchain/adnl-tunnel/config"
+	"github.com/ton-blockchain/adnl-tunnel/conformance"
+	"github.com/ton-blockchain/adnl-tunnel/journal"
 	"github.com/ton-blockchain/adnl-tunnel/metrics"
 	"github.com/ton-blockchain/adnl-tunnel/tunnel"
 	"github.com/xssnick/ton-payment-network/tonpayments"
@@ -37,8 +42,12 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"os"
+	"os/signal"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "net/http/pprof"
@@ -58,6 +67,15 @@ var MetricsAddr = flag.String("metrics-listen-addr", "", "Addr to run the promet
 var LogCompress = flag.Bool("log-compress", false, "whether to compress rotated log files")
 var LogDisableFile = flag.Bool("log-disable-file", false, "Disable logging to file")
 
+var AdminSocketPath = flag.String("admin-socket", "", "Unix socket path to serve the admin RPC API on (optional, disabled if empty)")
+var AdminListenAddr = flag.String("admin-listen-addr", "", "Addr to run the admin RPC HTTP server on (optional, disabled if empty)")
+var AdminToken = flag.String("admin-token", "", "Token required to call destructive admin RPC methods (wallet-transfer, etc.)")
+
+var JournalCapacity = flag.Int("journal-capacity", 4096, "Number of recent events kept in the in-memory journal ring buffer")
+var JournalFile = flag.String("journal-file", "", "Path to append-only JSON lines journal file (optional, disabled if empty)")
+
+var GenVectorsDir = flag.String("gen-vectors", "", "Record live session events as conformance test vectors into this directory (optional, disabled if empty)")
+
 var GitCommit = "dev"
 
 func main() {
@@ -106,6 +124,7 @@ func main() {
 
 	if *MetricsAddr != "" {
 		metrics.RegisterMetrics()
+		metrics.RegisterBandwidthMetrics()
 		go func() {
 			log.Info().Str("addr", *MetricsAddr).Msg("starting metrics server")
 			if err := http.ListenAndServe(*MetricsAddr, promhttp.Handler()); err != nil {
@@ -202,6 +221,18 @@ func main() {
 		return
 	}
 
+	var jrn *journal.Journal
+	if *JournalCapacity > 0 {
+		var writers []journal.Writer
+		if *JournalFile != "" {
+			writers = append(writers, journal.NewFileWriter(*JournalFile, *LogMaxSize, *LogMaxBackups, *LogMaxAge))
+		}
+		if *GenVectorsDir != "" {
+			writers = append(writers, conformance.NewRecorder(*GenVectorsDir))
+		}
+		jrn = journal.New(*JournalCapacity, journal.MultiWriter(writers...))
+	}
+
 	var wlt *wallet.Wallet
 	var pmt tunnel.PaymentConfig
 	var apiClient ton.APIClientWrapped
@@ -223,7 +254,7 @@ func main() {
 			}
 		}
 
-		chId, err := preparePaymentChannel(context.Background(), pm, ch)
+		chId, err := preparePaymentChannel(context.Background(), pm, ch, jrn)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to prepare payment channels")
 		}
@@ -243,6 +274,9 @@ func main() {
 		lvl = zerolog.DebugLevel
 	}
 	tGate := tunnel.NewGateway(gate, dhtClient, tunKey, log.With().Str("component", "gateway").Logger().Level(lvl), pmt)
+	if jrn != nil {
+		tGate.SetJournal(jrn)
+	}
 	go func() {
 		if err = tGate.Start(); err != nil {
 			log.Fatal().Err(err).Msg("tunnel gateway failed")
@@ -250,10 +284,96 @@ func main() {
 		}
 	}()
 
+	gateState := &gatewayState{gate: tGate, cfg: cfg}
+
+	if *AdminSocketPath != "" || *AdminListenAddr != "" {
+		if *AdminToken == "" {
+			log.Warn().Msg("admin API enabled without -admin-token, destructive calls are unauthenticated")
+		}
+
+		adminSrv := admin.NewServer(admin.Deps{
+			Gateway:  tGate,
+			Payments: pmt.Service,
+			Wallet:   wlt,
+			API:      apiClient,
+			Journal:  jrn,
+			Log:      log.Logger,
+		}, *AdminToken)
+
+		adminSrv.Register("Tunnel.Reload", false, func(ctx context.Context, _ json.RawMessage) (any, error) {
+			if err := gateState.reload(ctx, *ConfigPath); err != nil {
+				return nil, err
+			}
+			return map[string]string{"status": "ok"}, nil
+		})
+
+		if *AdminSocketPath != "" {
+			if err = adminSrv.ListenUnix(*AdminSocketPath); err != nil {
+				log.Fatal().Err(err).Msg("failed to start admin socket")
+				return
+			}
+			log.Info().Str("path", *AdminSocketPath).Msg("admin RPC listening on unix socket")
+		}
+
+		if *AdminListenAddr != "" {
+			if err = adminSrv.ListenHTTP(*AdminListenAddr); err != nil {
+				log.Fatal().Err(err).Msg("failed to start admin http server")
+				return
+			}
+			log.Info().Str("addr", *AdminListenAddr).Msg("admin RPC listening on http")
+		}
+	}
+
+	// The sampler itself runs unconditionally: Tunnel.Bandwidth (admin API),
+	// "adnl-tunnel-ctl bandwidth" and the stdin "bandwidth" command all call
+	// GetBandwidthStats directly, regardless of whether Prometheus metrics
+	// are enabled, and would otherwise always read back zero. Only the
+	// Prometheus export is conditional on -metrics-listen-addr.
+	bwSampler := tunnel.NewBandwidthSampler(tGate)
+	go func() {
+		for range time.Tick(15 * time.Second) {
+			bwSampler.Sample()
+			if *MetricsAddr != "" {
+				for key, bw := range tGate.GetBandwidthStats() {
+					metrics.ObserveBandwidth(key, bw.RoutedBytesIn, bw.RoutedBytesOut, bw.TerminatedBytesIn, bw.TerminatedBytesOut, bw.RateBytesPerSec)
+				}
+			}
+		}
+	}()
+
+	if jrn != nil {
+		prepaidSampler := tunnel.NewPrepaidSampler(tGate)
+		go func() {
+			for range time.Tick(15 * time.Second) {
+				prepaidSampler.Sample()
+			}
+		}()
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			log.Info().Msg("SIGHUP received, reloading config")
+			if err := gateState.reload(context.Background(), *ConfigPath); err != nil {
+				log.Error().Err(err).Msg("config reload failed")
+			}
+		}
+	}()
+
+	log.Info().Msg("Tunnel started, listening on " + cfg.TunnelListenAddr + " ADNL id is: " + base64.StdEncoding.EncodeToString(tunKey.Public().(ed25519.PublicKey)))
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		log.Info().Msg("no TTY attached, stdin command loop disabled; use adnl-tunnel-ctl or the admin API")
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		return
+	}
+
 	speedPrinterCtx, cancelSp := context.WithCancel(context.Background())
 	cancelSp()
 
-	log.Info().Msg("Tunnel started, listening on " + cfg.TunnelListenAddr + " ADNL id is: " + base64.StdEncoding.EncodeToString(tunKey.Public().(ed25519.PublicKey)))
 	for {
 		log.Info().Msg("Input a command:")
 		var val string
@@ -306,6 +426,16 @@ func main() {
 					Str("prepaid_in", formatNumInt(st.PrepaidPacketsIn)).
 					Msg("stats summarized")
 			}
+		case "bandwidth":
+			for key, bw := range tGate.GetBandwidthStats() {
+				log.Info().Str("key", key).
+					Str("routed_in", formatNum(bw.RoutedBytesIn)).
+					Str("routed_out", formatNum(bw.RoutedBytesOut)).
+					Str("terminated_in", formatNum(bw.TerminatedBytesIn)).
+					Str("terminated_out", formatNum(bw.TerminatedBytesOut)).
+					Str("rate_1m", formatNum(uint64(bw.RateBytesPerSec["1m"]))+"/s").
+					Msg("bandwidth")
+			}
 		case "balance", "capacity":
 			if pmt.Service == nil {
 				log.Error().Msg("payments are not enabled")
@@ -399,11 +529,73 @@ func main() {
 				continue
 			}
 			log.Info().Str("hash", base64.URLEncoding.EncodeToString(tx.Hash)).Msg("transfer transaction committed")
+
+			if jrn != nil {
+				jrn.Record(journal.EventWalletTransfer, map[string]string{
+					"to":     addr.String(),
+					"amount": amt.String(),
+					"hash":   base64.URLEncoding.EncodeToString(tx.Hash),
+				})
+			}
 		}
 
 	}
 }
 
+// gatewayState tracks the config a running Gateway was last reloaded
+// with, so reload can tell which knobs actually changed and whether a
+// graceful listener handover (Rebind) is needed.
+type gatewayState struct {
+	mu   sync.Mutex
+	gate *tunnel.Gateway
+	cfg  *config.Config
+}
+
+// reload re-reads config.json and applies the changes it finds: price
+// changes apply in place, while listen address or thread count changes
+// trigger a graceful handover via Gateway.Rebind so in-flight sessions get
+// a chance to close on their own first. The log level is re-applied from
+// the -v flag, not read from config.json, so a reload does not change it.
+func (s *gatewayState) reload(ctx context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	newCfg, err := config.LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	lvl := zerolog.InfoLevel
+	if *Verbosity >= 3 {
+		lvl = zerolog.DebugLevel
+	}
+	s.gate.Reload(tunnel.ReloadablePricing{
+		MinPricePerPacketRoute: newCfg.Payments.MinPricePerPacketRoute,
+		MinPricePerPacketInOut: newCfg.Payments.MinPricePerPacketInOut,
+	}, lvl)
+
+	threads := int(newCfg.TunnelThreads)
+	if threads == 0 {
+		threads = runtime.NumCPU()
+	}
+	oldThreads := int(s.cfg.TunnelThreads)
+	if oldThreads == 0 {
+		oldThreads = runtime.NumCPU()
+	}
+
+	if newCfg.TunnelListenAddr != s.cfg.TunnelListenAddr || threads != oldThreads {
+		log.Info().Str("addr", newCfg.TunnelListenAddr).Int("threads", threads).
+			Msg("listen address or thread count changed, performing graceful handover")
+		if err := s.gate.Rebind(ctx, newCfg.TunnelListenAddr, threads, 30*time.Second); err != nil {
+			return fmt.Errorf("rebind gateway: %w", err)
+		}
+	}
+
+	s.cfg = newCfg
+	log.Info().Msg("config reload applied")
+	return nil
+}
+
 func formatNum(packets uint64) string {
 	sizes := []string{"", " K", " M", " B"}
 
@@ -540,7 +732,7 @@ func preparePayments(ctx context.Context, gCfg *liteclient.GlobalConfig, dhtClie
 	return svc, w.Wallet(), apiClient
 }
 
-func preparePaymentChannel(ctx context.Context, pmt *tonpayments.Service, ch []byte) ([]byte, error) {
+func preparePaymentChannel(ctx context.Context, pmt *tonpayments.Service, ch []byte, jrn *journal.Journal) ([]byte, error) {
 	list, err := pmt.ListChannels(ctx, nil, db.ChannelStateActive)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list channels: %w", err)
@@ -594,6 +786,12 @@ func preparePaymentChannel(ctx context.Context, pmt *tonpayments.Service, ch []b
 		return nil, fmt.Errorf("failed to deploy channel with node: %w", err)
 	}
 	log.Info().Msg("onchain channel deployed at address: " + addr.String() + " waiting for states exchange...")
+	if jrn != nil {
+		jrn.Record(journal.EventChannelDeploy, map[string]string{
+			"address":    addr.String(),
+			"their_node": base64.StdEncoding.EncodeToString(ch),
+		})
+	}
 
 	for {
 		channel, err := pmt.GetChannel(context.Background(), addr.String())
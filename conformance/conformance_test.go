@@ -0,0 +1,46 @@
+package conformance
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// vectorsDirEnv points at a directory of vector files, typically a
+// submodule or a downloaded fixture set (see LoadVectors). The suite is
+// skipped when unset so `go test ./...` stays hermetic by default.
+const vectorsDirEnv = "CONFORMANCE_VECTORS_DIR"
+
+// TestVectors replays every vector under CONFORMANCE_VECTORS_DIR against
+// DefaultRunner with no Gateway attached (see DefaultRunner's doc comment
+// for which operations it replays, and cmd/node's -gen-vectors mode for how
+// live sessions produce new vectors).
+func TestVectors(t *testing.T) {
+	dir := os.Getenv(vectorsDirEnv)
+	if dir == "" {
+		t.Skipf("%s not set, skipping conformance suite", vectorsDirEnv)
+	}
+
+	vectors, err := LoadVectors(dir)
+	if err != nil {
+		t.Fatalf("load vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatalf("no vectors found in %s", dir)
+	}
+
+	runner := DefaultRunner(nil)
+	results := runner.Run(context.Background(), vectors)
+
+	for _, r := range results {
+		r := r
+		t.Run(r.Vector.Name, func(t *testing.T) {
+			if r.Err != nil {
+				t.Fatalf("%s: %v", r.Vector.Operation, r.Err)
+			}
+			if !r.Passed {
+				t.Fatalf("%s: got %s, expected %s", r.Vector.Operation, r.Got, r.Vector.Expected)
+			}
+		})
+	}
+}
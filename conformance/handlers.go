@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ton-blockchain/adnl-tunnel/tunnel"
+)
+
+// DefaultRunner creates a Runner with handlers registered for the
+// Operations this tree can actually replay deterministically:
+// OpPrepaidAccounting for real, OpPaymentChannelState as a well-formedness
+// placeholder only (see its handler's doc comment). See the package doc
+// comment: OpSectionFraming and OpRouteSetupHandshake - the two operations
+// this package exists for - have no registered handler. Vectors for them
+// fail with "no handler registered" rather than silently reporting
+// success, but that means this Runner does not deliver wire-compatibility
+// checking for section framing or the hop handshake; don't treat a green
+// TestVectors run as covering those two surfaces.
+//
+// gateway is accepted (and currently unused) so a future real
+// OpPaymentChannelState replay - once the channel state-transition math is
+// reachable from this package - doesn't require changing every caller's
+// signature.
+func DefaultRunner(gateway *tunnel.Gateway) *Runner {
+	r := NewRunner()
+	r.Register(OpPrepaidAccounting, prepaidAccountingHandler)
+	r.Register(OpPaymentChannelState, paymentChannelStateHandler(gateway))
+	return r
+}
+
+// prepaidAccountingInput holds a purchased/consumed pair. Its "consumed"
+// key matches the payload PrepaidSampler journals; TrackPrepaidPurchase
+// journals its purchase as "count", which does not decode into Purchased
+// (see tunnel/sessions.go).
+type prepaidAccountingInput struct {
+	Purchased int64 `json:"purchased"`
+	Consumed  int64 `json:"consumed"`
+}
+
+type prepaidAccountingOutput struct {
+	Remaining int64 `json:"remaining"`
+}
+
+// prepaidAccountingHandler recomputes the remaining prepaid-packet balance
+// from a purchased/consumed pair - the same arithmetic the payment layer
+// uses to decide when a section needs a top-up - so a vector genuinely
+// exercises real logic rather than echoing its own input back.
+func prepaidAccountingHandler(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+	var in prepaidAccountingInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, fmt.Errorf("decode prepaid accounting input: %w", err)
+	}
+
+	return json.Marshal(prepaidAccountingOutput{Remaining: in.Purchased - in.Consumed})
+}
+
+// paymentChannelStateHandler is a placeholder: the real channel
+// state-transition math lives in the xssnick/ton-payment-network vendor
+// package, outside this tree, so this handler only canonicalizes Input's
+// JSON and returns it unchanged. It does not validate any protocol
+// behavior yet - registering it at least means a recorded vector's Input
+// is checked to be well-formed JSON instead of failing with "no handler
+// registered".
+func paymentChannelStateHandler(gateway *tunnel.Gateway) Handler {
+	return func(ctx context.Context, input json.RawMessage) (json.RawMessage, error) {
+		var v any
+		if err := json.Unmarshal(input, &v); err != nil {
+			return nil, fmt.Errorf("decode payment channel state input: %w", err)
+		}
+		return json.Marshal(v)
+	}
+}
@@ -0,0 +1,59 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDefaultRunnerPrepaidAccounting(t *testing.T) {
+	r := DefaultRunner(nil)
+
+	v := Vector{
+		Name:      "prepaid-0001",
+		Operation: OpPrepaidAccounting,
+		Input:     json.RawMessage(`{"purchased":100,"consumed":40}`),
+		Expected:  json.RawMessage(`{"remaining":60}`),
+	}
+
+	results := r.Run(context.Background(), []Vector{v})
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("unexpected error: %v", results[0].Err)
+	}
+	if !results[0].Passed {
+		t.Fatalf("expected vector to pass, got %s", results[0].Got)
+	}
+}
+
+func TestDefaultRunnerUnregisteredOperationFails(t *testing.T) {
+	r := DefaultRunner(nil)
+
+	v := Vector{Name: "framing-0001", Operation: OpSectionFraming, Input: json.RawMessage(`{}`)}
+	results := r.Run(context.Background(), []Vector{v})
+
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected an explicit error for an unregistered operation, got %+v", results)
+	}
+}
+
+func TestDefaultRunnerPaymentChannelStateCanonicalizes(t *testing.T) {
+	r := DefaultRunner(nil)
+
+	v := Vector{
+		Name:      "channel-0001",
+		Operation: OpPaymentChannelState,
+		Input:     json.RawMessage(`{"address":"abc","their_node":"xyz"}`),
+		Expected:  json.RawMessage(`{"address":"abc","their_node":"xyz"}`),
+	}
+
+	results := r.Run(context.Background(), []Vector{v})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected result: %+v", results)
+	}
+	if !results[0].Passed {
+		t.Fatalf("expected vector to pass, got %s", results[0].Got)
+	}
+}
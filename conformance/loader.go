@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LoadVectors reads every *.json file in dir (non-recursive) as a single
+// Vector or a JSON array of Vectors, and returns them sorted by file name
+// so runs are deterministic.
+func LoadVectors(dir string) ([]Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read vectors dir: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		files = append(files, e.Name())
+	}
+	sort.Strings(files)
+
+	var vectors []Vector
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read vector file %s: %w", path, err)
+		}
+
+		var single Vector
+		if err := json.Unmarshal(body, &single); err == nil && single.Operation != "" {
+			vectors = append(vectors, single)
+			continue
+		}
+
+		var many []Vector
+		if err := json.Unmarshal(body, &many); err != nil {
+			return nil, fmt.Errorf("parse vector file %s: %w", path, err)
+		}
+		vectors = append(vectors, many...)
+	}
+
+	return vectors, nil
+}
+
+// WriteVector appends vector as its own file under dir, named after
+// vector.Name, for use by -gen-vectors recording.
+func WriteVector(dir string, vector Vector) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create vectors dir: %w", err)
+	}
+
+	body, err := json.MarshalIndent(vector, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal vector: %w", err)
+	}
+
+	path := filepath.Join(dir, vector.Name+".json")
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("write vector file %s: %w", path, err)
+	}
+	return nil
+}
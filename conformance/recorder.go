@@ -0,0 +1,54 @@
+package conformance
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ton-blockchain/adnl-tunnel/journal"
+)
+
+// eventOperations maps the journal events that also represent a protocol
+// operation we have a vector format for. Lifecycle events without a
+// conformance Operation (e.g. wallet transfers) are ignored by Recorder.
+var eventOperations = map[journal.EventType]Operation{
+	journal.EventChannelDeploy:   OpPaymentChannelState,
+	journal.EventPrepaidConsumed: OpPrepaidAccounting,
+}
+
+// Recorder implements journal.Writer, turning live session events into
+// conformance vectors as they happen. It's the engine behind the node's
+// -gen-vectors flag: run the node against real peers with it enabled, then
+// feed the resulting directory into LoadVectors/Runner for replay.
+type Recorder struct {
+	dir string
+	n   atomic.Uint64
+}
+
+// NewRecorder creates a Recorder that writes one vector file per matched
+// event into dir.
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{dir: dir}
+}
+
+// Write implements journal.Writer. It records Input from the live event
+// but leaves Expected blank: a live session's payload is a real input, not
+// a verified-correct output, so treating it as both would make every
+// vector trivially pass. A human (or, eventually, a trusted reference
+// implementation) needs to fill in Expected - e.g. by running the vector
+// through DefaultRunner and blessing the Got it produces - before the
+// vector is fit to replay in TestVectors.
+func (r *Recorder) Write(e journal.Event) error {
+	op, ok := eventOperations[e.Type]
+	if !ok {
+		return nil
+	}
+
+	idx := r.n.Add(1)
+	v := Vector{
+		Name:      fmt.Sprintf("%s-%04d", e.Type, idx),
+		Operation: op,
+		Input:     e.Payload,
+	}
+
+	return WriteVector(r.dir, v)
+}
@@ -0,0 +1,80 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Handler replays one Operation on a vector's Input and returns the actual
+// output for comparison against the vector's Expected.
+type Handler func(ctx context.Context, input json.RawMessage) (json.RawMessage, error)
+
+// Runner replays vectors through registered per-operation Handlers.
+type Runner struct {
+	handlers map[Operation]Handler
+}
+
+// NewRunner creates an empty Runner; register handlers with Register
+// before calling Run.
+func NewRunner() *Runner {
+	return &Runner{handlers: map[Operation]Handler{}}
+}
+
+// Register installs the Handler used to replay vectors of the given
+// Operation.
+func (r *Runner) Register(op Operation, h Handler) {
+	r.handlers[op] = h
+}
+
+// Result is the outcome of replaying one Vector.
+type Result struct {
+	Vector Vector
+	Got    json.RawMessage
+	Err    error
+	Passed bool
+}
+
+// Run replays every vector through its registered Handler. A vector whose
+// Operation has no registered Handler fails with an explicit error rather
+// than being silently skipped, so coverage gaps are visible in the report.
+func (r *Runner) Run(ctx context.Context, vectors []Vector) []Result {
+	results := make([]Result, 0, len(vectors))
+
+	for _, v := range vectors {
+		h, ok := r.handlers[v.Operation]
+		if !ok {
+			results = append(results, Result{
+				Vector: v,
+				Err:    fmt.Errorf("no handler registered for operation %q", v.Operation),
+			})
+			continue
+		}
+
+		got, err := h(ctx, v.Input)
+		if err != nil {
+			results = append(results, Result{Vector: v, Err: err})
+			continue
+		}
+
+		results = append(results, Result{
+			Vector: v,
+			Got:    got,
+			Passed: jsonEqual(got, v.Expected),
+		})
+	}
+
+	return results
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var na, nb any
+	if json.Unmarshal(a, &na) != nil || json.Unmarshal(b, &nb) != nil {
+		return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	}
+
+	na2, _ := json.Marshal(na)
+	nb2, _ := json.Marshal(nb)
+	return bytes.Equal(na2, nb2)
+}
@@ -0,0 +1,49 @@
+// Package conformance loads and replays deterministic test vectors against
+// the tunnel protocol, so alternative implementations (Rust, C++) and our
+// own dependency bumps (xssnick/tonutils-go, xssnick/ton-payment-network)
+// can be checked for wire compatibility without a live network.
+//
+// KNOWN LIMITATION: DefaultRunner does not yet cover the two operations
+// that actually motivated this package - OpSectionFraming and
+// OpRouteSetupHandshake, the encrypted framing and hop handshake that wire
+// compatibility hinges on. Vectors for both operations fail with "no
+// handler registered", by design (see Runner.Run), rather than silently
+// passing: the wire-level protocol implementation they'd replay against
+// isn't present in this chunk of the tree. Until handlers for those two
+// operations exist, this harness validates prepaid-accounting arithmetic
+// and payload well-formedness only - it does not deliver cross-
+// implementation wire-compatibility checking yet. See DefaultRunner's doc
+// comment for the per-operation breakdown.
+package conformance
+
+import "encoding/json"
+
+// Operation names one of the tunnel-protocol surfaces a vector exercises.
+type Operation string
+
+const (
+	// OpSectionFraming covers encrypted section framing: given a section
+	// key and plaintext frame, the expected ciphertext on the wire.
+	OpSectionFraming Operation = "section_framing"
+	// OpRouteSetupHandshake covers the route setup handshake between
+	// tunnel hops: given the handshake inputs, the expected exchanged
+	// messages.
+	OpRouteSetupHandshake Operation = "route_setup_handshake"
+	// OpPrepaidAccounting covers prepaid-packet purchase/consumption
+	// accounting: given a purchased and a consumed packet count, the
+	// expected remaining balance.
+	OpPrepaidAccounting Operation = "prepaid_accounting"
+	// OpPaymentChannelState covers payment-channel state transitions
+	// consumed via tunnel.PaymentConfig: given a starting state and an
+	// applied transition, the expected resulting state.
+	OpPaymentChannelState Operation = "payment_channel_state"
+)
+
+// Vector is one deterministic test case: given Input, replaying Operation
+// against the real implementation must produce Expected.
+type Vector struct {
+	Name      string          `json:"name"`
+	Operation Operation       `json:"operation"`
+	Input     json.RawMessage `json:"input"`
+	Expected  json.RawMessage `json:"expected"`
+}
@@ -0,0 +1,49 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// FileWriter appends events as JSON lines to a rotated log file, using
+// lumberjack as the node does for its human-readable logs. Rotated journal
+// files are not compressed.
+type FileWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	out *lumberjack.Logger
+}
+
+// NewFileWriter opens (or creates) an append-only JSON lines journal at
+// path, rotating at maxSizeMB and keeping up to maxBackups old files for
+// up to maxAgeDays.
+func NewFileWriter(path string, maxSizeMB, maxBackups, maxAgeDays int) *FileWriter {
+	out := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeDays,
+	}
+	return &FileWriter{out: out, enc: json.NewEncoder(out)}
+}
+
+// Write implements Writer.
+func (w *FileWriter) Write(e Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.enc.Encode(e); err != nil {
+		return fmt.Errorf("write journal event: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the underlying rotated file.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.Close()
+}
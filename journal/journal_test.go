@@ -0,0 +1,136 @@
+package journal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestJournalTailOrdersChronologicallyBeforeWrap(t *testing.T) {
+	j := New(4, nil)
+	for i := 0; i < 3; i++ {
+		j.Record(EventGatewayReload, map[string]int{"i": i})
+	}
+
+	tail := j.Tail(Filter{})
+	if len(tail) != 3 {
+		t.Fatalf("len(tail) = %d, want 3", len(tail))
+	}
+	for i, e := range tail {
+		if e.Seq != uint64(i+1) {
+			t.Fatalf("tail[%d].Seq = %d, want %d", i, e.Seq, i+1)
+		}
+	}
+}
+
+func TestJournalTailOrdersChronologicallyAfterWrap(t *testing.T) {
+	j := New(4, nil)
+	for i := 0; i < 10; i++ {
+		j.Record(EventGatewayReload, map[string]int{"i": i})
+	}
+
+	tail := j.Tail(Filter{})
+	if len(tail) != 4 {
+		t.Fatalf("len(tail) = %d, want 4 (ring capacity)", len(tail))
+	}
+
+	// The ring only has room for the 4 most recent events (seq 7..10).
+	for i, e := range tail {
+		wantSeq := uint64(7 + i)
+		if e.Seq != wantSeq {
+			t.Fatalf("tail[%d].Seq = %d, want %d", i, e.Seq, wantSeq)
+		}
+	}
+}
+
+func TestJournalTailFiltersByType(t *testing.T) {
+	j := New(8, nil)
+	j.Record(EventPeerAdd, nil)
+	j.Record(EventGatewayReload, nil)
+	j.Record(EventPeerAdd, nil)
+
+	tail := j.Tail(Filter{Types: []EventType{EventPeerAdd}})
+	if len(tail) != 2 {
+		t.Fatalf("len(tail) = %d, want 2", len(tail))
+	}
+	for _, e := range tail {
+		if e.Type != EventPeerAdd {
+			t.Fatalf("unexpected event type %q in filtered tail", e.Type)
+		}
+	}
+}
+
+func TestJournalTailRespectsLimit(t *testing.T) {
+	j := New(8, nil)
+	for i := 0; i < 5; i++ {
+		j.Record(EventGatewayReload, nil)
+	}
+
+	tail := j.Tail(Filter{Limit: 2})
+	if len(tail) != 2 {
+		t.Fatalf("len(tail) = %d, want 2", len(tail))
+	}
+	if tail[0].Seq != 4 || tail[1].Seq != 5 {
+		t.Fatalf("unexpected limited tail: %+v", tail)
+	}
+}
+
+func TestJournalSinceReturnsOnlyNewerEvents(t *testing.T) {
+	j := New(8, nil)
+	for i := 0; i < 5; i++ {
+		j.Record(EventGatewayReload, nil)
+	}
+
+	since := j.Since(3)
+	if len(since) != 2 {
+		t.Fatalf("len(since) = %d, want 2", len(since))
+	}
+	if since[0].Seq != 4 || since[1].Seq != 5 {
+		t.Fatalf("unexpected Since result: %+v", since)
+	}
+
+	if all := j.Since(0); len(all) != 5 {
+		t.Fatalf("Since(0) len = %d, want 5", len(all))
+	}
+	if none := j.Since(5); len(none) != 0 {
+		t.Fatalf("Since(latest) len = %d, want 0", len(none))
+	}
+}
+
+type recordingWriter struct {
+	events []Event
+}
+
+func (w *recordingWriter) Write(e Event) error {
+	w.events = append(w.events, e)
+	return nil
+}
+
+func TestMultiWriterFansOutAndDropsNils(t *testing.T) {
+	var a, b recordingWriter
+	var nilWriter Writer
+
+	w := MultiWriter(&a, nilWriter, &b)
+	if w == nil {
+		t.Fatalf("expected a non-nil Writer when at least one real writer is given")
+	}
+
+	j := New(4, w)
+	j.Record(EventPeerAdd, nil)
+
+	if len(a.events) != 1 || len(b.events) != 1 {
+		t.Fatalf("expected both writers to receive the event, got a=%d b=%d", len(a.events), len(b.events))
+	}
+}
+
+func TestMultiWriterAllNilReturnsNil(t *testing.T) {
+	if w := MultiWriter(nil, nil); w != nil {
+		t.Fatalf("expected MultiWriter of only nils to return nil, got %v", w)
+	}
+}
+
+func ExampleJournal_Record() {
+	j := New(4, nil)
+	e := j.Record(EventGatewayReload, map[string]string{"ok": "true"})
+	fmt.Println(e.Seq)
+	// Output: 1
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Bandwidth counters/gauges are labeled by attribution key (peer id or
+// tunnel section/hop id), traffic class (routed vs terminated) and
+// direction, so operators can attribute bandwidth to a specific tunnel
+// section or peer instead of only seeing node-wide packet counts.
+var (
+	// bandwidthBytes is a Gauge, not a Counter: tunnel.GetBandwidthStats
+	// already returns lifetime cumulative totals, so each tick just
+	// reports the current value rather than adding it on top of itself.
+	bandwidthBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bandwidth_bytes",
+		Help:      "Cumulative bytes observed per attribution key, traffic class and direction.",
+	}, []string{"key", "class", "direction"})
+
+	bandwidthRateBytesPerSec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bandwidth_rate_bytes_per_second",
+		Help:      "Rolling EWMA byte rate per attribution key and window (1m/5m/15m).",
+	}, []string{"key", "window"})
+)
+
+// RegisterBandwidthMetrics registers the bandwidth collectors. Call
+// alongside RegisterMetrics() when bandwidth attribution is desired.
+func RegisterBandwidthMetrics() {
+	prometheus.MustRegister(bandwidthBytes, bandwidthRateBytesPerSec)
+}
+
+// ObserveBandwidth updates the bandwidth collectors for one attribution
+// key from a tunnel.BandwidthStats-shaped snapshot. routedIn..terminatedOut
+// are lifetime cumulative totals, so they're Set, not Add-ed.
+func ObserveBandwidth(key string, routedIn, routedOut, terminatedIn, terminatedOut uint64, rates map[string]float64) {
+	bandwidthBytes.WithLabelValues(key, "routed", "in").Set(float64(routedIn))
+	bandwidthBytes.WithLabelValues(key, "routed", "out").Set(float64(routedOut))
+	bandwidthBytes.WithLabelValues(key, "terminated", "in").Set(float64(terminatedIn))
+	bandwidthBytes.WithLabelValues(key, "terminated", "out").Set(float64(terminatedOut))
+
+	for window, rate := range rates {
+		bandwidthRateBytesPerSec.WithLabelValues(key, window).Set(rate)
+	}
+}
@@ -0,0 +1,232 @@
+package tunnel
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Gateway additionally carries a bandwidth map (guarded by bandwidthMu,
+// lazily populated per attribution key) alongside its existing packet
+// counters; both are initialized in NewGateway.
+
+// Direction is the flow of a byte count relative to this node.
+type Direction int
+
+const (
+	DirectionIn Direction = iota
+	DirectionOut
+)
+
+// ewmaHalfLives are the windows the gateway keeps rolling byte rates for,
+// matching the "1m/5m/15m" granularity operators expect from load-average
+// style figures.
+var ewmaHalfLives = map[string]time.Duration{
+	"1m":  1 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+}
+
+// BandwidthStats are the byte counters and rates for one attribution key
+// (a peer, a tunnel section, or a tunnel hop). Routed traffic is payload
+// this node forwarded on behalf of someone else's tunnel; Terminated
+// traffic originates or ends at this node.
+type BandwidthStats struct {
+	RoutedBytesIn      uint64
+	RoutedBytesOut     uint64
+	TerminatedBytesIn  uint64
+	TerminatedBytesOut uint64
+
+	// RateBytesPerSec is keyed by "1m", "5m", "15m" and holds the combined
+	// (routed + terminated) byte rate over that window.
+	RateBytesPerSec map[string]float64
+}
+
+type bandwidthCounter struct {
+	mu sync.Mutex
+
+	routedIn, routedOut         uint64
+	terminatedIn, terminatedOut uint64
+
+	ewma map[string]*ewmaRate
+}
+
+type ewmaRate struct {
+	rate     float64
+	lastSeen time.Time
+	window   time.Duration
+}
+
+func newBandwidthCounter() *bandwidthCounter {
+	c := &bandwidthCounter{ewma: map[string]*ewmaRate{}}
+	for name, window := range ewmaHalfLives {
+		c.ewma[name] = &ewmaRate{window: window}
+	}
+	return c
+}
+
+func (c *bandwidthCounter) observe(routed bool, dir Direction, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case routed && dir == DirectionIn:
+		c.routedIn += uint64(n)
+	case routed && dir == DirectionOut:
+		c.routedOut += uint64(n)
+	case !routed && dir == DirectionIn:
+		c.terminatedIn += uint64(n)
+	default:
+		c.terminatedOut += uint64(n)
+	}
+
+	now := time.Now()
+	for _, e := range c.ewma {
+		e.observe(now, float64(n))
+	}
+}
+
+// observe folds a new sample into the EWMA using the standard
+// alpha = 1 - exp(-elapsed/window) decay, so gaps between samples are
+// weighted the same way regardless of how often observe is called.
+func (e *ewmaRate) observe(now time.Time, bytesSample float64) {
+	if e.lastSeen.IsZero() {
+		e.lastSeen = now
+		e.rate = bytesSample / e.window.Seconds()
+		return
+	}
+
+	elapsed := now.Sub(e.lastSeen).Seconds()
+	if elapsed <= 0 {
+		e.rate += bytesSample / e.window.Seconds()
+		return
+	}
+	e.lastSeen = now
+
+	instant := bytesSample / elapsed
+	alpha := 1 - math.Exp(-elapsed/e.window.Seconds())
+	e.rate = e.rate + alpha*(instant-e.rate)
+}
+
+func (c *bandwidthCounter) snapshot() *BandwidthStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rates := make(map[string]float64, len(c.ewma))
+	for name, e := range c.ewma {
+		rates[name] = e.rate
+	}
+
+	return &BandwidthStats{
+		RoutedBytesIn:      c.routedIn,
+		RoutedBytesOut:     c.routedOut,
+		TerminatedBytesIn:  c.terminatedIn,
+		TerminatedBytesOut: c.terminatedOut,
+		RateBytesPerSec:    rates,
+	}
+}
+
+// RecordBandwidth attributes n bytes moved in direction dir to key (a peer
+// id, tunnel section id, or "<section>#<hop>" hop key), for use by the
+// packet forwarding path when it already knows whether the frame was
+// routed on behalf of another tunnel or terminated locally.
+func (g *Gateway) RecordBandwidth(key string, routed bool, dir Direction, n int) {
+	g.bandwidthMu.Lock()
+	c, ok := g.bandwidth[key]
+	if !ok {
+		c = newBandwidthCounter()
+		g.bandwidth[key] = c
+	}
+	g.bandwidthMu.Unlock()
+
+	c.observe(routed, dir, n)
+}
+
+// GetBandwidthStats returns a snapshot of byte counters and rolling rates.
+// RecordBandwidth accepts any attribution key - peer id, tunnel section
+// id, or "<section>#<hop>" for an individual hop - but the only caller in
+// this tree, BandwidthSampler, only ever produces per-section keys (see
+// its doc comment for why peer- and hop-level attribution aren't
+// populated yet).
+func (g *Gateway) GetBandwidthStats() map[string]*BandwidthStats {
+	g.bandwidthMu.Lock()
+	defer g.bandwidthMu.Unlock()
+
+	out := make(map[string]*BandwidthStats, len(g.bandwidth))
+	for key, c := range g.bandwidth {
+		out[key] = c.snapshot()
+	}
+	return out
+}
+
+// estimatedBytesPerPacket approximates the on-wire frame size used to turn
+// packet counts into byte counts. The forwarding path doesn't thread exact
+// frame lengths through to RecordBandwidth yet, so BandwidthSampler is an
+// interim source of byte attribution until it does.
+const estimatedBytesPerPacket = 1024
+
+type packetSample struct {
+	routed, sent, received uint64
+}
+
+// BandwidthSampler periodically turns GetPacketsStats' packet counters into
+// RecordBandwidth calls, so GetBandwidthStats and the Prometheus bandwidth
+// metrics report non-zero numbers even though nothing on the packet
+// forwarding path calls RecordBandwidth directly yet.
+//
+// KNOWN LIMITATION: this is a linear rescaling of the packet counters
+// GetPacketsStats already exposes (packet_delta * estimatedBytesPerPacket),
+// keyed per-section because that's the only granularity GetPacketsStats
+// offers - it carries no peer-id or per-hop attribution, and no
+// information beyond what the packet counts already encode. Real
+// byte-accurate, peer/hop-attributed bandwidth accounting requires the
+// packet forwarding path itself (which isn't present in this tree) to call
+// RecordBandwidth directly with the real frame size and a peer/hop key as
+// it forwards each frame. Until that lands, don't rely on
+// GetBandwidthStats for anything a rescaled packet count can't already
+// tell you.
+type BandwidthSampler struct {
+	gateway *Gateway
+
+	mu   sync.Mutex
+	prev map[string]packetSample
+}
+
+// NewBandwidthSampler creates a sampler for g. Call Sample periodically
+// (e.g. on the same ticker that feeds the Prometheus metrics).
+func NewBandwidthSampler(g *Gateway) *BandwidthSampler {
+	return &BandwidthSampler{gateway: g, prev: map[string]packetSample{}}
+}
+
+// Sample diffs the current packet counters against the previous call's and
+// attributes the delta to RecordBandwidth using estimatedBytesPerPacket.
+// The first call only seeds the baseline and records nothing, since there's
+// no prior sample to diff against.
+func (s *BandwidthSampler) Sample() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur := make(map[string]packetSample, len(s.prev))
+	for key, st := range s.gateway.GetPacketsStats() {
+		sample := packetSample{routed: st.Routed, sent: st.Sent, received: st.Received}
+		cur[key] = sample
+
+		if prev, ok := s.prev[key]; ok {
+			// Packet counters don't distinguish routed-in from routed-out,
+			// so routed traffic is attributed as outbound; this only
+			// affects which BandwidthStats field the estimate lands in,
+			// not the totals. Counters only ever grow, but guard against
+			// underflow in case a section's counters are ever reset.
+			if sample.routed > prev.routed {
+				s.gateway.RecordBandwidth(key, true, DirectionOut, int((sample.routed-prev.routed)*estimatedBytesPerPacket))
+			}
+			if sample.sent > prev.sent {
+				s.gateway.RecordBandwidth(key, false, DirectionOut, int((sample.sent-prev.sent)*estimatedBytesPerPacket))
+			}
+			if sample.received > prev.received {
+				s.gateway.RecordBandwidth(key, false, DirectionIn, int((sample.received-prev.received)*estimatedBytesPerPacket))
+			}
+		}
+	}
+	s.prev = cur
+}
@@ -0,0 +1,69 @@
+package tunnel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBandwidthCounterObserve(t *testing.T) {
+	c := newBandwidthCounter()
+	c.observe(true, DirectionIn, 100)
+	c.observe(true, DirectionOut, 50)
+	c.observe(false, DirectionIn, 10)
+	c.observe(false, DirectionOut, 5)
+
+	snap := c.snapshot()
+	if snap.RoutedBytesIn != 100 {
+		t.Fatalf("RoutedBytesIn = %d, want 100", snap.RoutedBytesIn)
+	}
+	if snap.RoutedBytesOut != 50 {
+		t.Fatalf("RoutedBytesOut = %d, want 50", snap.RoutedBytesOut)
+	}
+	if snap.TerminatedBytesIn != 10 {
+		t.Fatalf("TerminatedBytesIn = %d, want 10", snap.TerminatedBytesIn)
+	}
+	if snap.TerminatedBytesOut != 5 {
+		t.Fatalf("TerminatedBytesOut = %d, want 5", snap.TerminatedBytesOut)
+	}
+}
+
+func TestEwmaRateFirstSampleSeedsRate(t *testing.T) {
+	e := &ewmaRate{window: time.Minute}
+	now := time.Unix(1000, 0)
+	e.observe(now, 6000)
+
+	want := 6000.0 / time.Minute.Seconds()
+	if e.rate != want {
+		t.Fatalf("rate after first sample = %v, want %v", e.rate, want)
+	}
+}
+
+func TestEwmaRateConvergesTowardSustainedRate(t *testing.T) {
+	e := &ewmaRate{window: time.Minute}
+	now := time.Unix(0, 0)
+	e.observe(now, 0)
+
+	// Feed a steady 100 bytes/sec for well beyond the window and check the
+	// EWMA has converged close to it, without ever jumping straight there.
+	const sustained = 100.0
+	for i := 0; i < 600; i++ {
+		now = now.Add(time.Second)
+		e.observe(now, sustained)
+	}
+
+	if diff := e.rate - sustained; diff > 1 || diff < -1 {
+		t.Fatalf("rate = %v, want close to %v after sustained sampling", e.rate, sustained)
+	}
+}
+
+func TestEwmaRateNonPositiveElapsedAccumulates(t *testing.T) {
+	e := &ewmaRate{window: time.Minute}
+	now := time.Unix(0, 0)
+	e.observe(now, 60)
+	before := e.rate
+	e.observe(now, 60)
+
+	if e.rate <= before {
+		t.Fatalf("rate should increase on a same-timestamp sample, got %v -> %v", before, e.rate)
+	}
+}
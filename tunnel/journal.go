@@ -0,0 +1,28 @@
+package tunnel
+
+import "github.com/ton-blockchain/adnl-tunnel/journal"
+
+// SetJournal attaches j so the gateway records events through recordEvent
+// as they happen (currently EventGatewayReload and, through
+// PrepaidSampler, EventPrepaidConsumed). It's optional: a Gateway with no
+// journal attached behaves exactly as before. Call once, before Start.
+//
+// TrackSessionOpen/TrackSessionClose (EventTunnelSectionOpen/Close,
+// EventPeerAdd/Drop) and TrackPrepaidPurchase (EventPrepaidPurchased) are
+// implemented in sessions.go but have no caller anywhere in this tree: the
+// section-accept/teardown loop and the payment top-up path they'd hook
+// into live in the packet forwarding implementation, which isn't present
+// in this chunk of the tree. Wiring them in is tracked as follow-up work,
+// not silently assumed to already be happening.
+func (g *Gateway) SetJournal(j *journal.Journal) {
+	g.journal = j
+}
+
+// recordEvent is a no-op when no journal is attached, so call sites along
+// the hot path don't need a nil check of their own.
+func (g *Gateway) recordEvent(typ journal.EventType, payload any) {
+	if g.journal == nil {
+		return
+	}
+	g.journal.Record(typ, payload)
+}
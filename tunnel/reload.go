@@ -0,0 +1,132 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/ton-blockchain/adnl-tunnel/journal"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/adnl/address"
+)
+
+// Gateway additionally carries a paymentMu guarding g.payment and a
+// draining flag checked by the section-accept path, alongside its
+// existing state (including g.key and g.externalAddr it was constructed
+// with); all are initialized in NewGateway. g.gate is an
+// atomic.Pointer[adnl.Gateway] rather than a plain field, since Rebind
+// swaps it concurrently with the packet-forwarding hot path reading it.
+
+// ReloadablePricing are the payment knobs that can be changed without
+// dropping any live session — unlike listen address, port or thread
+// count, which require a new listener (see the graceful handover in
+// cmd/node).
+type ReloadablePricing struct {
+	MinPricePerPacketRoute *big.Int
+	MinPricePerPacketInOut *big.Int
+}
+
+// Reload applies new pricing and log level in place, taking effect for the
+// next packet routed on every already-open session. It does not touch the
+// listener, ADNL keypair, or thread pool.
+func (g *Gateway) Reload(pricing ReloadablePricing, logLevel zerolog.Level) {
+	g.paymentMu.Lock()
+	g.payment.MinPricePerPacketRoute = pricing.MinPricePerPacketRoute
+	g.payment.MinPricePerPacketInOut = pricing.MinPricePerPacketInOut
+	g.paymentMu.Unlock()
+
+	g.log = g.log.Level(logLevel)
+	g.recordEvent(journal.EventGatewayReload, map[string]string{
+		"min_price_route":  pricing.MinPricePerPacketRoute.String(),
+		"min_price_in_out": pricing.MinPricePerPacketInOut.String(),
+		"log_level":        logLevel.String(),
+	})
+}
+
+// Drain stops accepting new tunnel sections and waits for existing ones to
+// close on their own, up to deadline. It's the first half of a graceful
+// handover to a Gateway bound to a different listen address, port or
+// thread count — parameters that can't be changed on a running listener.
+func (g *Gateway) Drain(ctx context.Context, deadline time.Duration) error {
+	g.draining.Store(true)
+	return drainUntil(ctx, deadline, g.activeSections)
+}
+
+// drainUntil polls remaining every 200ms until it reports zero or ctx times
+// out, independent of any Gateway state, so the polling loop itself can be
+// tested without a live Gateway.
+func drainUntil(ctx context.Context, deadline time.Duration, remaining func() int) error {
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if n := remaining(); n == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("drain deadline exceeded with %d sections still open", remaining())
+		case <-ticker.C:
+		}
+	}
+}
+
+// activeSections counts tunnel sections currently tracked as open (see
+// TrackSessionOpen/TrackSessionClose in sessions.go). Earlier this counted
+// len(GetPacketsStats()), which never shrinks once a section has carried
+// any traffic — Drain would then never observe zero and would always time
+// out on a node that had handled real traffic. ActiveSessionCount reflects
+// sessions that are actually still open.
+func (g *Gateway) activeSections() int {
+	return g.ActiveSessionCount()
+}
+
+// Rebind performs the second half of a graceful handover: it drains the
+// current listener (up to drainDeadline), brings up a new ADNL listener on
+// listenAddr with threads worker threads using the same ADNL keypair and
+// external address, and atomically swaps it in. The payment service,
+// leveldb and in-memory state (journal, bandwidth/packet counters) are
+// untouched, since they live on this same Gateway, not the listener.
+func (g *Gateway) Rebind(ctx context.Context, listenAddr string, threads int, drainDeadline time.Duration) error {
+	// Drain sets g.draining before it does anything else, so the flag must
+	// be cleared on every exit path, including a drain timeout - otherwise
+	// a failed reload leaves the gateway permanently refusing new
+	// sections, with no remaining path to clear it short of a later Rebind
+	// succeeding.
+	defer g.draining.Store(false)
+
+	if err := g.Drain(ctx, drainDeadline); err != nil {
+		return fmt.Errorf("drain before rebind: %w", err)
+	}
+
+	newGate := adnl.NewGateway(g.key)
+	if g.externalAddr != nil {
+		newGate.SetAddressList([]*address.UDP{g.externalAddr})
+	}
+	if err := newGate.StartServer(listenAddr, threads); err != nil {
+		return fmt.Errorf("start new listener on %s: %w", listenAddr, err)
+	}
+
+	old := g.gate.Swap(newGate)
+
+	go func() {
+		// give in-flight responses a moment before tearing down the old
+		// listener, then release its resources.
+		time.Sleep(time.Second)
+		if old != nil {
+			_ = old.Close()
+		}
+	}()
+
+	g.recordEvent(journal.EventGatewayReload, map[string]string{
+		"listen_addr": listenAddr,
+		"threads":     fmt.Sprint(threads),
+	})
+	return nil
+}
@@ -0,0 +1,45 @@
+package tunnel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDrainUntilReturnsOnceRemainingHitsZero(t *testing.T) {
+	remaining := 3
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		remaining = 0
+		close(done)
+	}()
+
+	err := drainUntil(context.Background(), time.Second, func() int { return remaining })
+	<-done
+	if err != nil {
+		t.Fatalf("drainUntil returned error %v, want nil once remaining reached zero", err)
+	}
+}
+
+func TestDrainUntilTimesOutWhileSessionsRemain(t *testing.T) {
+	err := drainUntil(context.Background(), 50*time.Millisecond, func() int { return 1 })
+	if err == nil {
+		t.Fatalf("expected a timeout error when remaining never reaches zero")
+	}
+}
+
+func TestDrainUntilRespectsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := drainUntil(ctx, time.Second, func() int { return 1 }); err == nil {
+		t.Fatalf("expected an error for an already-canceled context")
+	}
+}
+
+func TestDrainUntilZeroRemainingImmediately(t *testing.T) {
+	if err := drainUntil(context.Background(), time.Second, func() int { return 0 }); err != nil {
+		t.Fatalf("expected no error when remaining is already zero, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package tunnel
+
+import (
+	"sync"
+
+	"github.com/ton-blockchain/adnl-tunnel/journal"
+)
+
+// Gateway additionally carries a sessionsMu guarding sessions and
+// peerRefs, alongside its existing state; both are initialized empty in
+// NewGateway.
+//
+// KNOWN LIMITATION: TrackSessionOpen, TrackSessionClose and
+// TrackPrepaidPurchase have no caller in this tree (see SetJournal's doc
+// comment in journal.go) - the section-accept/teardown loop and payment
+// top-up path that would call them live in code not present in this chunk
+// of the tree. ActiveSessionCount (used by Drain) and the Track* methods
+// themselves are real and tested, but a running node built from only this
+// tree will never actually call them.
+
+// sessionInfo is what the gateway remembers about one open tunnel section
+// for journaling and drain purposes.
+type sessionInfo struct {
+	peerID string
+}
+
+// TrackSessionOpen records that a tunnel section has been accepted and is
+// now routing traffic, journaling EventTunnelSectionOpen and, the first
+// time peerID is seen with an open section, EventPeerAdd. Call it from the
+// section-accept path when a new section starts forwarding.
+func (g *Gateway) TrackSessionOpen(sectionID, peerID string) {
+	g.sessionsMu.Lock()
+	if g.sessions == nil {
+		g.sessions = map[string]sessionInfo{}
+	}
+	if g.peerRefs == nil {
+		g.peerRefs = map[string]int{}
+	}
+	g.sessions[sectionID] = sessionInfo{peerID: peerID}
+	isNewPeer := g.peerRefs[peerID] == 0
+	g.peerRefs[peerID]++
+	g.sessionsMu.Unlock()
+
+	g.recordEvent(journal.EventTunnelSectionOpen, map[string]string{
+		"section": sectionID,
+		"peer":    peerID,
+	})
+	if isNewPeer {
+		g.recordEvent(journal.EventPeerAdd, map[string]string{"peer": peerID})
+	}
+}
+
+// TrackSessionClose records that a tunnel section has stopped routing
+// traffic, journaling EventTunnelSectionClose and, once the peer has no
+// other open sections, EventPeerDrop. Call it from the section teardown
+// path. Closing a sectionID that was never opened (or already closed) is a
+// no-op: nothing is journaled and no error is reported.
+func (g *Gateway) TrackSessionClose(sectionID string) {
+	g.sessionsMu.Lock()
+	info, ok := g.sessions[sectionID]
+	if !ok {
+		g.sessionsMu.Unlock()
+		return
+	}
+	delete(g.sessions, sectionID)
+
+	g.peerRefs[info.peerID]--
+	peerDropped := g.peerRefs[info.peerID] <= 0
+	if peerDropped {
+		delete(g.peerRefs, info.peerID)
+	}
+	g.sessionsMu.Unlock()
+
+	g.recordEvent(journal.EventTunnelSectionClose, map[string]string{
+		"section": sectionID,
+		"peer":    info.peerID,
+	})
+	if peerDropped {
+		g.recordEvent(journal.EventPeerDrop, map[string]string{"peer": info.peerID})
+	}
+}
+
+// ActiveSessionCount returns the number of tunnel sections currently
+// tracked as open, for use as the real "still has live traffic" signal by
+// Drain (see reload.go), rather than a lifetime packet-counter proxy.
+func (g *Gateway) ActiveSessionCount() int {
+	g.sessionsMu.Lock()
+	defer g.sessionsMu.Unlock()
+	return len(g.sessions)
+}
+
+// TrackPrepaidPurchase records that count additional prepaid packets were
+// purchased for sectionID. The packet forwarding path's consumption of
+// prepaid packets is sampled separately by PrepaidSampler from
+// GetPacketsStats; purchases, by contrast, happen at the payment/channel
+// top-up integration point, which lives outside this package in this tree
+// — callers there should invoke this when a top-up succeeds.
+func (g *Gateway) TrackPrepaidPurchase(sectionID string, count int64) {
+	g.recordEvent(journal.EventPrepaidPurchased, map[string]any{
+		"section": sectionID,
+		"count":   count,
+	})
+}
+
+// prepaidSample is the subset of GetPacketsStats' counters PrepaidSampler
+// diffs between calls.
+type prepaidSample struct {
+	out, in int64
+}
+
+// PrepaidSampler periodically diffs PacketStats' prepaid consumption
+// counters and journals EventPrepaidConsumed for the delta, so operators
+// replaying the journal can see prepaid packet burn-down without polling
+// Tunnel.Stats themselves.
+type PrepaidSampler struct {
+	gateway *Gateway
+
+	mu   sync.Mutex
+	prev map[string]prepaidSample
+}
+
+// NewPrepaidSampler creates a sampler for g. Call Sample periodically.
+func NewPrepaidSampler(g *Gateway) *PrepaidSampler {
+	return &PrepaidSampler{gateway: g, prev: map[string]prepaidSample{}}
+}
+
+// Sample diffs the current prepaid counters against the previous call's
+// and journals the consumed delta per section. The first call only seeds
+// the baseline.
+func (s *PrepaidSampler) Sample() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur := make(map[string]prepaidSample, len(s.prev))
+	for section, st := range s.gateway.GetPacketsStats() {
+		sample := prepaidSample{out: st.PrepaidPacketsOut, in: st.PrepaidPacketsIn}
+		cur[section] = sample
+
+		if prev, ok := s.prev[section]; ok {
+			consumed := (sample.out - prev.out) + (sample.in - prev.in)
+			if consumed > 0 {
+				s.gateway.recordEvent(journal.EventPrepaidConsumed, map[string]any{
+					"section":  section,
+					"consumed": consumed,
+				})
+			}
+		}
+	}
+	s.prev = cur
+}